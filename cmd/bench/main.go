@@ -0,0 +1,106 @@
+// Command bench drives sustained chirp-create traffic at chirpy's router
+// layer to compare the net/http and fasthttp backends under load. It
+// exercises only the routing/encoding path (no database), since that is
+// where the two backends differ in per-request allocation.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/winkles99/chirpy/internal/router"
+	"github.com/winkles99/chirpy/internal/router/fasthttprouter"
+	"github.com/winkles99/chirpy/internal/router/nethttprouter"
+)
+
+func main() {
+	engine := flag.String("engine", "nethttp", "server engine to benchmark: nethttp|fasthttp")
+	addr := flag.String("addr", "127.0.0.1:8091", "address to listen on")
+	requests := flag.Int("requests", 20000, "total number of POST /api/chirps requests to send")
+	concurrency := flag.Int("concurrency", 50, "number of concurrent clients")
+	flag.Parse()
+
+	var rt router.Router
+	switch *engine {
+	case "fasthttp":
+		rt = fasthttprouter.New()
+	case "nethttp":
+		rt = nethttprouter.New()
+	default:
+		log.Fatalf("unknown engine %q (want nethttp or fasthttp)", *engine)
+	}
+
+	rt.Handle("POST", "/api/chirps", handleCreateChirp)
+
+	go func() {
+		if err := rt.ListenAndServe(*addr); err != nil {
+			log.Fatalf("server exited: %v", err)
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	var memBefore runtime.MemStats
+	runtime.ReadMemStats(&memBefore)
+	start := time.Now()
+
+	runLoad(*addr, *requests, *concurrency)
+
+	elapsed := time.Since(start)
+	var memAfter runtime.MemStats
+	runtime.ReadMemStats(&memAfter)
+
+	fmt.Printf("engine=%s requests=%d concurrency=%d\n", *engine, *requests, *concurrency)
+	fmt.Printf("elapsed=%s req/s=%.0f\n", elapsed, float64(*requests)/elapsed.Seconds())
+	// The server under test runs in this same process (see the goroutine
+	// above), so these MemStats cover client+server allocations combined,
+	// not just the client's.
+	fmt.Printf("process mallocs=%d total_alloc=%dKB\n",
+		memAfter.Mallocs-memBefore.Mallocs, (memAfter.TotalAlloc-memBefore.TotalAlloc)/1024)
+}
+
+func handleCreateChirp(c router.Ctx) {
+	var req struct {
+		Body string `json:"body"`
+	}
+	if err := c.DecodeJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, map[string]string{"error": "Something went wrong"})
+		return
+	}
+	c.JSON(http.StatusCreated, map[string]string{"body": req.Body})
+}
+
+func runLoad(addr string, requests, concurrency int) {
+	client := &http.Client{Timeout: 5 * time.Second}
+	url := "http://" + addr + "/api/chirps"
+	payload := []byte(`{"body":"benchmarking chirpy under load"}`)
+
+	var wg sync.WaitGroup
+	perWorker := requests / concurrency
+	for i := 0; i < concurrency; i++ {
+		n := perWorker
+		if i < requests%concurrency {
+			n++
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < n; j++ {
+				resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+				if err != nil {
+					log.Printf("request failed: %v", err)
+					continue
+				}
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,96 @@
+// Package auth provides password hashing and JWT/refresh token helpers
+// used by the user authentication endpoints.
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrNoAuthHeaderIncluded is returned when an Authorization header is missing.
+var ErrNoAuthHeaderIncluded = errors.New("no auth header included in request")
+
+const issuer = "chirpy"
+
+// HashPassword returns the bcrypt hash of password.
+func HashPassword(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// CheckPasswordHash compares a plaintext password against a bcrypt hash.
+func CheckPasswordHash(password, hash string) error {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+}
+
+// MakeJWT creates a signed, short-lived access token for userID.
+func MakeJWT(userID uuid.UUID, tokenSecret string, expiresIn time.Duration) (string, error) {
+	now := time.Now().UTC()
+	claims := jwt.RegisteredClaims{
+		Issuer:    issuer,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
+		Subject:   userID.String(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(tokenSecret))
+}
+
+// ValidateJWT parses and validates a signed access token, returning the user ID from its subject.
+func ValidateJWT(tokenString, tokenSecret string) (uuid.UUID, error) {
+	claims := jwt.RegisteredClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, &claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(tokenSecret), nil
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if !token.Valid {
+		return uuid.Nil, errors.New("invalid token")
+	}
+	issuedBy, err := claims.GetIssuer()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if issuedBy != issuer {
+		return uuid.Nil, errors.New("invalid issuer")
+	}
+	subject, err := claims.GetSubject()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return uuid.Parse(subject)
+}
+
+// GetBearerToken extracts the token from an "Authorization: Bearer <token>"
+// header value. It takes the raw header value rather than an http.Header so
+// it works the same regardless of which server backend parsed the request.
+func GetBearerToken(authHeader string) (string, error) {
+	if authHeader == "" {
+		return "", ErrNoAuthHeaderIncluded
+	}
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	if token == authHeader {
+		return "", errors.New("malformed authorization header")
+	}
+	return strings.TrimSpace(token), nil
+}
+
+// MakeRefreshToken generates a random 256-bit opaque refresh token, hex-encoded.
+func MakeRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestHashPasswordAndCheckPasswordHash(t *testing.T) {
+	hash, err := HashPassword("correct horse battery staple")
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	if hash == "correct horse battery staple" {
+		t.Fatalf("HashPassword() returned the plaintext password unchanged")
+	}
+
+	if err := CheckPasswordHash("correct horse battery staple", hash); err != nil {
+		t.Errorf("CheckPasswordHash() with the correct password error = %v, want nil", err)
+	}
+	if err := CheckPasswordHash("wrong password", hash); err == nil {
+		t.Error("CheckPasswordHash() with the wrong password error = nil, want an error")
+	}
+}
+
+func TestMakeJWTAndValidateJWT(t *testing.T) {
+	userID := uuid.New()
+	token, err := MakeJWT(userID, "some-secret", time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT() error = %v", err)
+	}
+
+	gotID, err := ValidateJWT(token, "some-secret")
+	if err != nil {
+		t.Fatalf("ValidateJWT() error = %v", err)
+	}
+	if gotID != userID {
+		t.Errorf("ValidateJWT() = %v, want %v", gotID, userID)
+	}
+}
+
+func TestValidateJWTWrongSecret(t *testing.T) {
+	token, err := MakeJWT(uuid.New(), "some-secret", time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT() error = %v", err)
+	}
+
+	if _, err := ValidateJWT(token, "wrong-secret"); err == nil {
+		t.Error("ValidateJWT() with the wrong secret error = nil, want an error")
+	}
+}
+
+func TestValidateJWTExpired(t *testing.T) {
+	token, err := MakeJWT(uuid.New(), "some-secret", -time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT() error = %v", err)
+	}
+
+	if _, err := ValidateJWT(token, "some-secret"); err == nil {
+		t.Error("ValidateJWT() with an expired token error = nil, want an error")
+	}
+}
+
+func TestGetBearerToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		authHeader string
+		want       string
+		wantErr    bool
+	}{
+		{name: "valid header", authHeader: "Bearer abc123", want: "abc123"},
+		{name: "extra whitespace", authHeader: "Bearer   abc123  ", want: "abc123"},
+		{name: "missing header", authHeader: "", wantErr: true},
+		{name: "missing Bearer prefix", authHeader: "abc123", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetBearerToken(tt.authHeader)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetBearerToken(%q) error = %v, wantErr %v", tt.authHeader, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("GetBearerToken(%q) = %q, want %q", tt.authHeader, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeRefreshTokenIsUniqueAndHex(t *testing.T) {
+	a, err := MakeRefreshToken()
+	if err != nil {
+		t.Fatalf("MakeRefreshToken() error = %v", err)
+	}
+	b, err := MakeRefreshToken()
+	if err != nil {
+		t.Fatalf("MakeRefreshToken() error = %v", err)
+	}
+
+	if a == b {
+		t.Error("MakeRefreshToken() returned the same token twice")
+	}
+	if len(a) != 64 {
+		t.Errorf("MakeRefreshToken() len = %d, want 64 (32 bytes hex-encoded)", len(a))
+	}
+}
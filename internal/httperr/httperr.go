@@ -0,0 +1,165 @@
+// Package httperr centralizes how chirpy turns handler errors into HTTP
+// responses, structured slog logs, and (when configured) Sentry events,
+// instead of each handler calling log.Printf and writing its own error body.
+package httperr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/google/uuid"
+
+	"github.com/winkles99/chirpy/internal/router"
+)
+
+// Error is a handler error carrying the HTTP status code and user-facing
+// message it should produce. Wrap the underlying cause so it still reaches
+// Sentry for 5xx codes even though the client only sees msg; the request
+// log line does not include it.
+type Error struct {
+	Code int
+	Msg  string
+	err  error
+}
+
+func (e *Error) Error() string { return e.Msg }
+
+func (e *Error) Unwrap() error { return e.err }
+
+// Wrap builds an Error that the Middleware converts into a {"error": msg}
+// JSON response with the given status code.
+func Wrap(err error, code int, msg string) *Error {
+	return &Error{Code: code, Msg: msg, err: err}
+}
+
+var logger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+var sentryEnabled bool
+
+// InitSentry configures Sentry reporting for 5xx errors and panics. It is a
+// no-op if dsn is empty, so callers can always call it with os.Getenv("SENTRY_DSN").
+func InitSentry(dsn string) error {
+	if dsn == "" {
+		return nil
+	}
+	if err := sentry.Init(sentry.ClientOptions{Dsn: dsn}); err != nil {
+		return err
+	}
+	sentryEnabled = true
+	return nil
+}
+
+type ctxKey string
+
+const (
+	requestIDKey ctxKey = "httperr.requestID"
+	userIDKey    ctxKey = "httperr.userID"
+)
+
+// WithUserID attaches the authenticated user's ID to ctx. Middleware sets
+// that user on a Sentry report only when the ID is already on the context of
+// the Ctx that Middleware receives; an ID attached inside the handler it
+// wraps, on a copy made by WithContext, is not seen by Middleware.
+func WithUserID(ctx context.Context, userID uuid.UUID) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// UserIDFromContext returns the user ID attached by WithUserID, if any.
+func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Value(userIDKey).(uuid.UUID)
+	return userID, ok
+}
+
+// RequestIDFromContext returns the request ID the Middleware generated for
+// the in-flight request, if any.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey).(string)
+	return requestID, ok
+}
+
+// Handler is a handler that reports failure via its return value instead of
+// writing its own error response.
+type Handler func(router.Ctx) error
+
+// Middleware adapts h into a router.Handler that: tags the request with a
+// request ID, recovers panics, converts a returned error (unwrapping an
+// *Error for its status code and message) into a JSON error response,
+// forwards 5xx errors and panics to Sentry, and logs every request as
+// structured JSON via slog.
+func Middleware(h Handler) router.Handler {
+	return func(c router.Ctx) {
+		start := time.Now()
+		requestID := uuid.NewString()
+		c = c.WithContext(context.WithValue(c.Context(), requestIDKey, requestID))
+
+		defer func() {
+			if rec := recover(); rec != nil {
+				err := fmt.Errorf("panic: %v", rec)
+				report(c, requestID, err)
+				c.JSON(500, map[string]string{"error": "Internal Server Error"})
+				logRequest(c, requestID, start)
+				return
+			}
+			logRequest(c, requestID, start)
+		}()
+
+		if err := h(c); err != nil {
+			code, msg := 500, "Internal Server Error"
+			var httpErr *Error
+			if errors.As(err, &httpErr) {
+				code, msg = httpErr.Code, httpErr.Msg
+			}
+			if code >= 500 {
+				report(c, requestID, err)
+			}
+			// A WrapHandler-wrapped handler already wrote its own response
+			// (StatusCode() is nonzero) before surfacing its status as an
+			// error below, so don't write a second, generic one over it.
+			if c.StatusCode() == 0 {
+				c.JSON(code, map[string]string{"error": msg})
+			}
+		}
+	}
+}
+
+// WrapHandler adapts a plain router.Handler (one that already writes its own
+// response, including its own error bodies) so it still gets request-ID
+// tagging, structured logging, panic recovery, and Sentry reporting of 5xx
+// responses from Middleware.
+func WrapHandler(h router.Handler) router.Handler {
+	return Middleware(func(c router.Ctx) error {
+		h(c)
+		if c.StatusCode() >= 500 {
+			return fmt.Errorf("handler wrote status %d", c.StatusCode())
+		}
+		return nil
+	})
+}
+
+func report(c router.Ctx, requestID string, err error) {
+	if !sentryEnabled {
+		return
+	}
+	sentry.WithScope(func(scope *sentry.Scope) {
+		scope.SetTag("request_id", requestID)
+		if userID, ok := UserIDFromContext(c.Context()); ok {
+			scope.SetUser(sentry.User{ID: userID.String()})
+		}
+		sentry.CaptureException(err)
+	})
+}
+
+func logRequest(c router.Ctx, requestID string, start time.Time) {
+	logger.Info("request",
+		"request_id", requestID,
+		"method", c.Method(),
+		"path", c.Path(),
+		"status", c.StatusCode(),
+		"latency_ms", time.Since(start).Milliseconds(),
+	)
+}
@@ -0,0 +1,157 @@
+package httperr
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/winkles99/chirpy/internal/router"
+)
+
+// fakeCtx is a minimal router.Ctx test double. status and body are pointers
+// so that the copy Middleware makes via WithContext still writes back to the
+// instance the test holds, mirroring how nethttprouter.ctx shares status.
+type fakeCtx struct {
+	ctx    context.Context
+	status *int
+	body   *interface{}
+}
+
+func newFakeCtx() *fakeCtx {
+	var body interface{}
+	return &fakeCtx{ctx: context.Background(), status: new(int), body: &body}
+}
+
+func (c *fakeCtx) Context() context.Context { return c.ctx }
+
+func (c *fakeCtx) WithContext(ctx context.Context) router.Ctx {
+	return &fakeCtx{ctx: ctx, status: c.status, body: c.body}
+}
+
+func (c *fakeCtx) Method() string                   { return "GET" }
+func (c *fakeCtx) Path() string                     { return "/test" }
+func (c *fakeCtx) PathValue(name string) string     { return "" }
+func (c *fakeCtx) Query(name string) string         { return "" }
+func (c *fakeCtx) Header(name string) string        { return "" }
+func (c *fakeCtx) DecodeJSON(dst interface{}) error { return nil }
+
+func (c *fakeCtx) JSON(statusCode int, payload interface{}) {
+	*c.status = statusCode
+	*c.body = payload
+}
+
+func (c *fakeCtx) WriteText(statusCode int, body string) {
+	*c.status = statusCode
+	*c.body = body
+}
+
+func (c *fakeCtx) Redirect(statusCode int, url string) { *c.status = statusCode }
+
+func (c *fakeCtx) StatusCode() int { return *c.status }
+
+func errorBody(t *testing.T, body interface{}) string {
+	t.Helper()
+	m, ok := body.(map[string]string)
+	if !ok {
+		t.Fatalf("response body = %#v, want map[string]string", body)
+	}
+	return m["error"]
+}
+
+func TestMiddlewareConvertsWrappedErrorToItsStatusAndMessage(t *testing.T) {
+	c := newFakeCtx()
+	h := Handler(func(router.Ctx) error {
+		return Wrap(errors.New("no rows"), 404, "chirp not found")
+	})
+
+	Middleware(h)(c)
+
+	if c.StatusCode() != 404 {
+		t.Errorf("StatusCode() = %d, want 404", c.StatusCode())
+	}
+	if got := errorBody(t, *c.body); got != "chirp not found" {
+		t.Errorf("error body = %q, want %q", got, "chirp not found")
+	}
+}
+
+func TestMiddlewareDefaultsPlainErrorTo500(t *testing.T) {
+	c := newFakeCtx()
+	h := Handler(func(router.Ctx) error { return errors.New("boom") })
+
+	Middleware(h)(c)
+
+	if c.StatusCode() != 500 {
+		t.Errorf("StatusCode() = %d, want 500", c.StatusCode())
+	}
+	if got := errorBody(t, *c.body); got != "Internal Server Error" {
+		t.Errorf("error body = %q, want %q", got, "Internal Server Error")
+	}
+}
+
+func TestMiddlewareRecoversPanicAndWrites500(t *testing.T) {
+	c := newFakeCtx()
+	h := Handler(func(router.Ctx) error { panic("kaboom") })
+
+	Middleware(h)(c)
+
+	if c.StatusCode() != 500 {
+		t.Errorf("StatusCode() = %d, want 500", c.StatusCode())
+	}
+	if got := errorBody(t, *c.body); got != "Internal Server Error" {
+		t.Errorf("error body = %q, want %q", got, "Internal Server Error")
+	}
+}
+
+func TestMiddlewareLeavesResponseUntouchedOnSuccess(t *testing.T) {
+	c := newFakeCtx()
+	h := Handler(func(router.Ctx) error { return nil })
+
+	Middleware(h)(c)
+
+	if c.StatusCode() != 0 {
+		t.Errorf("StatusCode() = %d, want 0 (handler wrote nothing)", c.StatusCode())
+	}
+}
+
+func TestWrapHandlerPreservesA5xxResponseWrittenByTheHandler(t *testing.T) {
+	c := newFakeCtx()
+	h := router.Handler(func(c router.Ctx) {
+		c.JSON(503, map[string]string{"error": "database unavailable"})
+	})
+
+	WrapHandler(h)(c)
+
+	if c.StatusCode() != 503 {
+		t.Errorf("StatusCode() = %d, want 503", c.StatusCode())
+	}
+	if got := errorBody(t, *c.body); got != "database unavailable" {
+		t.Errorf("error body = %q, want the handler's own message %q", got, "database unavailable")
+	}
+}
+
+func TestWrapHandlerLeaves4xxResponsesUntouched(t *testing.T) {
+	c := newFakeCtx()
+	h := router.Handler(func(c router.Ctx) {
+		c.JSON(404, map[string]string{"error": "chirp not found"})
+	})
+
+	WrapHandler(h)(c)
+
+	if c.StatusCode() != 404 {
+		t.Errorf("StatusCode() = %d, want 404", c.StatusCode())
+	}
+	if got := errorBody(t, *c.body); got != "chirp not found" {
+		t.Errorf("error body = %q, want %q", got, "chirp not found")
+	}
+}
+
+func TestWrapHandlerRecoversPanic(t *testing.T) {
+	c := newFakeCtx()
+	h := router.Handler(func(router.Ctx) { panic("kaboom") })
+
+	WrapHandler(h)(c)
+
+	if c.StatusCode() != 500 {
+		t.Errorf("StatusCode() = %d, want 500", c.StatusCode())
+	}
+}
@@ -0,0 +1,172 @@
+// Package metrics holds chirpy's Prometheus instrumentation: per-route
+// request counters and latency histograms, database query timings, and
+// in-flight request gauges. It also keeps a small rolling window of recent
+// per-route latencies so /admin/metrics can show top routes by p95 without
+// a PromQL engine.
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/winkles99/chirpy/internal/router"
+)
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "chirpy_http_requests_total",
+		Help: "Total HTTP requests, labeled by route and status class.",
+	}, []string{"route", "status"})
+
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "chirpy_http_request_duration_seconds",
+		Help:    "HTTP request latency in seconds, labeled by route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route"})
+
+	requestsInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "chirpy_http_requests_in_flight",
+		Help: "In-flight HTTP requests, labeled by route.",
+	}, []string{"route"})
+
+	dbQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "chirpy_db_query_duration_seconds",
+		Help:    "Database query latency in seconds, labeled by query name.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"query"})
+
+	fileServerHitsCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "chirpy_fileserver_hits_total",
+		Help: "Total requests served by the file server.",
+	})
+)
+
+var fileServerHits atomic.Int64
+
+// RecordFileServerHit records a single file server request.
+func RecordFileServerHit() {
+	fileServerHitsCounter.Inc()
+	fileServerHits.Add(1)
+}
+
+// FileServerHits returns the number of file server requests recorded so far.
+func FileServerHits() int64 { return fileServerHits.Load() }
+
+// Observe wraps h to record request counters, latency histograms, and an
+// in-flight gauge for route, and feeds the rolling p95 window used by
+// TopRoutesByP95. Call it at route registration time.
+func Observe(route string, h router.Handler) router.Handler {
+	return func(c router.Ctx) {
+		gauge := requestsInFlight.WithLabelValues(route)
+		gauge.Inc()
+		defer gauge.Dec()
+
+		start := time.Now()
+		h(c)
+		elapsed := time.Since(start)
+
+		requestsTotal.WithLabelValues(route, statusClass(c.StatusCode())).Inc()
+		requestDuration.WithLabelValues(route).Observe(elapsed.Seconds())
+		recentSamples.record(route, elapsed.Seconds())
+	}
+}
+
+func statusClass(status int) string {
+	if status == 0 {
+		return "unknown"
+	}
+	return fmt.Sprintf("%dxx", status/100)
+}
+
+// ObserveDBQuery records the latency of a single named database query.
+// Typical use: defer metrics.ObserveDBQuery("CreateChirp")().
+func ObserveDBQuery(query string) func() {
+	start := time.Now()
+	return func() {
+		dbQueryDuration.WithLabelValues(query).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Gather renders the default Prometheus registry in text exposition format,
+// for handlers that write their response through router.Ctx rather than a
+// raw net/http.ResponseWriter.
+func Gather() (string, error) {
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	enc := expfmt.NewEncoder(&buf, expfmt.NewFormat(expfmt.TypeTextPlain))
+	for _, family := range families {
+		if err := enc.Encode(family); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
+
+// sampleWindow bounds how many recent latencies are kept per route for the
+// p95 estimate; older samples are dropped.
+const sampleWindow = 256
+
+var recentSamples = &routeSamples{data: make(map[string][]float64)}
+
+type routeSamples struct {
+	mu   sync.Mutex
+	data map[string][]float64
+}
+
+func (s *routeSamples) record(route string, seconds float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	samples := append(s.data[route], seconds)
+	if len(samples) > sampleWindow {
+		samples = samples[len(samples)-sampleWindow:]
+	}
+	s.data[route] = samples
+}
+
+// RouteSummary is one row of the top-routes-by-p95 view.
+type RouteSummary struct {
+	Route string
+	Count int
+	P95Ms float64
+}
+
+func (s *routeSamples) topByP95(n int) []RouteSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	summaries := make([]RouteSummary, 0, len(s.data))
+	for route, samples := range s.data {
+		if len(samples) == 0 {
+			continue
+		}
+		sorted := append([]float64(nil), samples...)
+		sort.Float64s(sorted)
+		p95 := sorted[int(float64(len(sorted)-1)*0.95)]
+		summaries = append(summaries, RouteSummary{Route: route, Count: len(sorted), P95Ms: p95 * 1000})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].P95Ms > summaries[j].P95Ms })
+	if len(summaries) > n {
+		summaries = summaries[:n]
+	}
+	return summaries
+}
+
+// TopRoutesByP95 returns up to n routes with the highest approximate p95
+// latency, based on a rolling window of recent samples.
+func TopRoutesByP95(n int) []RouteSummary {
+	return recentSamples.topByP95(n)
+}
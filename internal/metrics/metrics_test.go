@@ -0,0 +1,84 @@
+package metrics
+
+import "testing"
+
+func TestStatusClass(t *testing.T) {
+	tests := []struct {
+		status int
+		want   string
+	}{
+		{status: 0, want: "unknown"},
+		{status: 200, want: "2xx"},
+		{status: 201, want: "2xx"},
+		{status: 404, want: "4xx"},
+		{status: 500, want: "5xx"},
+	}
+
+	for _, tt := range tests {
+		if got := statusClass(tt.status); got != tt.want {
+			t.Errorf("statusClass(%d) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestRouteSamplesRecordTrimsToWindow(t *testing.T) {
+	s := &routeSamples{data: make(map[string][]float64)}
+
+	for i := 0; i < sampleWindow+10; i++ {
+		s.record("/chirps", float64(i))
+	}
+
+	samples := s.data["/chirps"]
+	if len(samples) != sampleWindow {
+		t.Fatalf("len(samples) = %d, want %d", len(samples), sampleWindow)
+	}
+	// The oldest 10 samples (0..9) should have been dropped, leaving
+	// 10..sampleWindow+9 in order.
+	if want := float64(10); samples[0] != want {
+		t.Errorf("samples[0] = %v, want %v", samples[0], want)
+	}
+	if want := float64(sampleWindow + 9); samples[len(samples)-1] != want {
+		t.Errorf("samples[last] = %v, want %v", samples[len(samples)-1], want)
+	}
+}
+
+func TestTopByP95(t *testing.T) {
+	s := &routeSamples{data: make(map[string][]float64)}
+
+	// /slow has a higher p95 than /fast despite fewer samples.
+	for i := 1; i <= 10; i++ {
+		s.record("/fast", float64(i)/1000) // 0.001..0.010s
+	}
+	for i := 1; i <= 4; i++ {
+		s.record("/slow", float64(i)/10) // 0.1..0.4s
+	}
+
+	got := s.topByP95(10)
+	if len(got) != 2 {
+		t.Fatalf("len(got) = %d, want 2", len(got))
+	}
+	if got[0].Route != "/slow" {
+		t.Errorf("got[0].Route = %q, want /slow (higher p95 first)", got[0].Route)
+	}
+	if got[0].Count != 4 {
+		t.Errorf("got[0].Count = %d, want 4", got[0].Count)
+	}
+	// len-1=3, 3*0.95=2.85 -> index 2 -> sorted [0.1,0.2,0.3,0.4][2] = 0.3s = 300ms.
+	if want := 300.0; got[0].P95Ms != want {
+		t.Errorf("got[0].P95Ms = %v, want %v", got[0].P95Ms, want)
+	}
+
+	if got := s.topByP95(1); len(got) != 1 {
+		t.Fatalf("topByP95(1) returned %d routes, want 1", len(got))
+	} else if got[0].Route != "/slow" {
+		t.Errorf("topByP95(1)[0].Route = %q, want /slow", got[0].Route)
+	}
+}
+
+func TestTopByP95SkipsRoutesWithNoSamples(t *testing.T) {
+	s := &routeSamples{data: map[string][]float64{"/empty": {}}}
+
+	if got := s.topByP95(10); len(got) != 0 {
+		t.Errorf("topByP95() = %v, want empty", got)
+	}
+}
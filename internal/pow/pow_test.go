@@ -0,0 +1,156 @@
+package pow
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// solve brute-forces a nonce meeting difficulty for seed. Tests use a low
+// difficulty so this stays fast.
+func solve(seed string, difficulty int) string {
+	for nonce := 0; ; nonce++ {
+		n := strconv.Itoa(nonce)
+		if leadingZeroBits(seed, n) >= difficulty {
+			return n
+		}
+	}
+}
+
+func header(c Challenge, nonce string) string {
+	return fmt.Sprintf("%s:%d:%s:%s", c.Seed, c.ExpiresAt.Unix(), nonce, c.Token)
+}
+
+func TestVerifyAcceptsASolvedChallenge(t *testing.T) {
+	v := NewVerifier("test-secret", 10)
+
+	challenge, err := NewChallenge("test-secret", 4)
+	if err != nil {
+		t.Fatalf("NewChallenge() error = %v", err)
+	}
+
+	nonce := solve(challenge.Seed, challenge.Difficulty)
+	if err := v.Verify(header(challenge, nonce), challenge.Difficulty); err != nil {
+		t.Errorf("Verify() error = %v, want nil", err)
+	}
+}
+
+func TestVerifyRejectsSeedReuse(t *testing.T) {
+	v := NewVerifier("test-secret", 10)
+
+	challenge, err := NewChallenge("test-secret", 4)
+	if err != nil {
+		t.Fatalf("NewChallenge() error = %v", err)
+	}
+
+	nonce := solve(challenge.Seed, challenge.Difficulty)
+	h := header(challenge, nonce)
+
+	if err := v.Verify(h, challenge.Difficulty); err != nil {
+		t.Fatalf("first Verify() error = %v, want nil", err)
+	}
+	if err := v.Verify(h, challenge.Difficulty); err != ErrSeedReused {
+		t.Errorf("second Verify() error = %v, want %v", err, ErrSeedReused)
+	}
+}
+
+func TestVerifyRejectsWrongSecret(t *testing.T) {
+	v := NewVerifier("test-secret", 10)
+
+	challenge, err := NewChallenge("a-different-secret", 4)
+	if err != nil {
+		t.Fatalf("NewChallenge() error = %v", err)
+	}
+
+	nonce := solve(challenge.Seed, challenge.Difficulty)
+	if err := v.Verify(header(challenge, nonce), challenge.Difficulty); err != ErrBadToken {
+		t.Errorf("Verify() error = %v, want %v", err, ErrBadToken)
+	}
+}
+
+func TestVerifyRejectsExpiredChallenge(t *testing.T) {
+	v := NewVerifier("test-secret", 10)
+
+	expired := time.Now().UTC().Add(-time.Minute)
+	challenge := Challenge{
+		Seed:       "deadbeef",
+		Difficulty: 0,
+		ExpiresAt:  expired,
+		Token:      sign("test-secret", "deadbeef", expired),
+	}
+
+	nonce := solve(challenge.Seed, challenge.Difficulty)
+	if err := v.Verify(header(challenge, nonce), challenge.Difficulty); err != ErrExpired {
+		t.Errorf("Verify() error = %v, want %v", err, ErrExpired)
+	}
+}
+
+func TestVerifyRejectsInsufficientWork(t *testing.T) {
+	v := NewVerifier("test-secret", 10)
+
+	challenge, err := NewChallenge("test-secret", 4)
+	if err != nil {
+		t.Fatalf("NewChallenge() error = %v", err)
+	}
+
+	// Require more work than the caller actually did.
+	nonce := solve(challenge.Seed, 0)
+	if err := v.Verify(header(challenge, nonce), challenge.Difficulty+16); err != ErrInsufficientWork {
+		t.Errorf("Verify() error = %v, want %v", err, ErrInsufficientWork)
+	}
+}
+
+func TestVerifyRejectsMissingAndMalformedHeaders(t *testing.T) {
+	v := NewVerifier("test-secret", 10)
+
+	tests := []struct {
+		name   string
+		header string
+		want   error
+	}{
+		{name: "missing", header: "", want: ErrMissingHeader},
+		{name: "too few fields", header: "seed:1:nonce", want: ErrMalformedHeader},
+		{name: "non-numeric expiry", header: "seed:notanumber:nonce:token", want: ErrMalformedHeader},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := v.Verify(tt.header, 0); err != tt.want {
+				t.Errorf("Verify(%q) error = %v, want %v", tt.header, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsumeEvictsLeastRecentlyUsedOnceOverCapacity(t *testing.T) {
+	v := NewVerifier("test-secret", 2)
+
+	if !v.consume("a") {
+		t.Fatal("consume(a) = false, want true")
+	}
+	if !v.consume("b") {
+		t.Fatal("consume(b) = false, want true")
+	}
+	// Pushes "a" out of the capacity-2 LRU.
+	if !v.consume("c") {
+		t.Fatal("consume(c) = false, want true")
+	}
+
+	if !v.consume("a") {
+		t.Error("consume(a) after eviction = false, want true (a should have been forgotten)")
+	}
+	if v.consume("c") {
+		t.Error("consume(c) after re-consuming = true, want false (c is still within capacity)")
+	}
+}
+
+func TestLeadingZeroBitsCountsAcrossByteBoundaries(t *testing.T) {
+	// sha256("known:342") = 0076...; its first byte is zero and its second
+	// byte (0x76 = 0b01110110) has one leading zero bit, for 9 total -
+	// precomputed independently of leadingZeroBits so the test can't just
+	// reproduce a miscount in the function under test.
+	if got := leadingZeroBits("known", "342"); got != 9 {
+		t.Errorf("leadingZeroBits() = %d, want 9", got)
+	}
+}
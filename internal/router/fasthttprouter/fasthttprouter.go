@@ -0,0 +1,230 @@
+// Package fasthttprouter implements router.Router on top of valyala/fasthttp,
+// trading net/http's per-request allocations for fasthttp's pooled
+// RequestCtx. Selected via SERVER_ENGINE=fasthttp for sustained write load.
+package fasthttprouter
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/winkles99/chirpy/internal/router"
+)
+
+// Router is a router.Router backed by fasthttp. Routes are matched by a
+// simple linear scan over registered routes; chirpy has a small, fixed set
+// of endpoints, so this stays cheap and keeps the matcher dependency-free.
+type Router struct {
+	routes     []route
+	filePrefix string
+	fileFS     *fasthttp.FS
+	onFileHit  func()
+}
+
+type route struct {
+	method   string // empty matches any method
+	segments []string
+	isPrefix bool // pattern ended in "/": matches path and everything under it
+	handler  router.Handler
+}
+
+// New returns an empty Router.
+func New() *Router {
+	return &Router{}
+}
+
+func (rt *Router) Handle(method, pattern string, h router.Handler) {
+	rt.routes = append(rt.routes, newRoute(method, pattern, h))
+}
+
+func (rt *Router) HandleAny(pattern string, h router.Handler) {
+	rt.routes = append(rt.routes, newRoute("", pattern, h))
+}
+
+func (rt *Router) HandleFiles(pathPrefix, dir string, onHit func()) {
+	rt.filePrefix = pathPrefix
+	rt.fileFS = &fasthttp.FS{
+		Root:               dir,
+		IndexNames:         []string{"index.html"},
+		PathRewrite:        fasthttp.NewPathPrefixStripper(len(pathPrefix)),
+		GenerateIndexPages: false,
+	}
+	rt.onFileHit = onHit
+}
+
+func (rt *Router) ListenAndServe(addr string) error {
+	return fasthttp.ListenAndServe(addr, rt.serve)
+}
+
+func (rt *Router) serve(rc *fasthttp.RequestCtx) {
+	path := string(rc.Path())
+	method := string(rc.Method())
+
+	if rt.fileFS != nil && strings.HasPrefix(path, rt.filePrefix) {
+		if rt.onFileHit != nil {
+			rt.onFileHit()
+		}
+		rt.fileFS.NewRequestHandler()(rc)
+		return
+	}
+
+	// Exact routes take precedence over subtree ("/"-suffixed) routes
+	// regardless of registration order, matching net/http.ServeMux semantics.
+	for _, rte := range rt.routes {
+		if rte.isPrefix || (rte.method != "" && rte.method != method) {
+			continue
+		}
+		params, ok := rte.match(path)
+		if !ok {
+			continue
+		}
+		rte.handler(&ctx{rc: rc, pathParams: params, status: new(int)})
+		return
+	}
+
+	// Subtree routes: the longest matching prefix wins, so e.g. the root "/"
+	// redirect wouldn't shadow a future "/api/" catch-all.
+	var best *route
+	for i := range rt.routes {
+		rte := &rt.routes[i]
+		if !rte.isPrefix || (rte.method != "" && rte.method != method) {
+			continue
+		}
+		if !rte.matchesPrefix(path) {
+			continue
+		}
+		if best == nil || len(rte.segments) > len(best.segments) {
+			best = rte
+		}
+	}
+	if best != nil {
+		best.handler(&ctx{rc: rc, status: new(int)})
+		return
+	}
+
+	rc.SetStatusCode(fasthttp.StatusNotFound)
+}
+
+func newRoute(method, pattern string, h router.Handler) route {
+	return route{
+		method:   method,
+		segments: splitPath(pattern),
+		isPrefix: strings.HasSuffix(pattern, "/"),
+		handler:  h,
+	}
+}
+
+func splitPath(path string) []string {
+	trimmed := strings.Trim(path, "/")
+	if trimmed == "" {
+		return nil
+	}
+	return strings.Split(trimmed, "/")
+}
+
+func (rte route) match(path string) (map[string]string, bool) {
+	parts := splitPath(path)
+	if len(parts) != len(rte.segments) {
+		return nil, false
+	}
+
+	var params map[string]string
+	for i, seg := range rte.segments {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			if params == nil {
+				params = make(map[string]string, 1)
+			}
+			params[seg[1:len(seg)-1]] = parts[i]
+			continue
+		}
+		if seg != parts[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// matchesPrefix reports whether path falls under rte's pattern, i.e. path
+// equals the pattern or is nested beneath it. Used for "/"-suffixed subtree
+// routes such as the root redirect.
+func (rte route) matchesPrefix(path string) bool {
+	parts := splitPath(path)
+	if len(parts) < len(rte.segments) {
+		return false
+	}
+	for i, seg := range rte.segments {
+		if seg != parts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ctx adapts a fasthttp.RequestCtx to router.Ctx. status is tracked
+// separately rather than read back from rc.Response.StatusCode(), which
+// defaults to 200 even when nothing has been written; status is a pointer
+// so it is shared with copies made by WithContext.
+type ctx struct {
+	rc         *fasthttp.RequestCtx
+	goCtx      context.Context
+	pathParams map[string]string
+	status     *int
+}
+
+func (c *ctx) Context() context.Context {
+	if c.goCtx != nil {
+		return c.goCtx
+	}
+	return context.Background()
+}
+
+func (c *ctx) WithContext(newCtx context.Context) router.Ctx {
+	return &ctx{rc: c.rc, goCtx: newCtx, pathParams: c.pathParams, status: c.status}
+}
+
+func (c *ctx) Method() string { return string(c.rc.Method()) }
+
+func (c *ctx) Path() string { return string(c.rc.Path()) }
+
+func (c *ctx) PathValue(name string) string { return c.pathParams[name] }
+
+func (c *ctx) Query(name string) string {
+	return string(c.rc.QueryArgs().Peek(name))
+}
+
+func (c *ctx) Header(name string) string {
+	return string(c.rc.Request.Header.Peek(name))
+}
+
+func (c *ctx) DecodeJSON(dst interface{}) error {
+	return json.Unmarshal(c.rc.PostBody(), dst)
+}
+
+func (c *ctx) JSON(statusCode int, payload interface{}) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		*c.status = fasthttp.StatusInternalServerError
+		c.rc.SetStatusCode(fasthttp.StatusInternalServerError)
+		return
+	}
+	*c.status = statusCode
+	c.rc.Response.Header.SetContentType("application/json")
+	c.rc.SetStatusCode(statusCode)
+	c.rc.SetBody(body)
+}
+
+func (c *ctx) WriteText(statusCode int, body string) {
+	*c.status = statusCode
+	c.rc.Response.Header.SetContentType("text/plain; charset=utf-8")
+	c.rc.SetStatusCode(statusCode)
+	c.rc.SetBodyString(body)
+}
+
+func (c *ctx) Redirect(statusCode int, url string) {
+	*c.status = statusCode
+	c.rc.Redirect(url, statusCode)
+}
+
+func (c *ctx) StatusCode() int { return *c.status }
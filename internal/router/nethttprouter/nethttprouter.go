@@ -0,0 +1,98 @@
+// Package nethttprouter implements router.Router on top of the standard
+// library net/http package. It is chirpy's default server backend.
+package nethttprouter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/winkles99/chirpy/internal/router"
+)
+
+// Router is a router.Router backed by http.ServeMux.
+type Router struct {
+	mux *http.ServeMux
+}
+
+// New returns an empty Router.
+func New() *Router {
+	return &Router{mux: http.NewServeMux()}
+}
+
+func (rt *Router) Handle(method, pattern string, h router.Handler) {
+	rt.mux.HandleFunc(method+" "+pattern, func(w http.ResponseWriter, r *http.Request) {
+		h(&ctx{w: w, r: r, status: new(int)})
+	})
+}
+
+func (rt *Router) HandleAny(pattern string, h router.Handler) {
+	rt.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		h(&ctx{w: w, r: r, status: new(int)})
+	})
+}
+
+func (rt *Router) HandleFiles(pathPrefix, dir string, onHit func()) {
+	fileHandler := http.StripPrefix(pathPrefix, http.FileServer(http.Dir(dir)))
+	rt.mux.Handle(pathPrefix, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if onHit != nil {
+			onHit()
+		}
+		fileHandler.ServeHTTP(w, r)
+	}))
+}
+
+func (rt *Router) ListenAndServe(addr string) error {
+	server := &http.Server{Addr: addr, Handler: rt.mux}
+	return server.ListenAndServe()
+}
+
+// ctx adapts an http.ResponseWriter/*http.Request pair to router.Ctx.
+// status is a pointer so it is shared with copies made by WithContext.
+type ctx struct {
+	w      http.ResponseWriter
+	r      *http.Request
+	status *int
+}
+
+func (c *ctx) Context() context.Context { return c.r.Context() }
+
+func (c *ctx) WithContext(newCtx context.Context) router.Ctx {
+	return &ctx{w: c.w, r: c.r.WithContext(newCtx), status: c.status}
+}
+
+func (c *ctx) Method() string { return c.r.Method }
+
+func (c *ctx) Path() string { return c.r.URL.Path }
+
+func (c *ctx) PathValue(name string) string { return c.r.PathValue(name) }
+
+func (c *ctx) Query(name string) string { return c.r.URL.Query().Get(name) }
+
+func (c *ctx) Header(name string) string { return c.r.Header.Get(name) }
+
+func (c *ctx) DecodeJSON(dst interface{}) error {
+	return json.NewDecoder(c.r.Body).Decode(dst)
+}
+
+func (c *ctx) JSON(statusCode int, payload interface{}) {
+	*c.status = statusCode
+	c.w.Header().Set("Content-Type", "application/json")
+	c.w.WriteHeader(statusCode)
+	json.NewEncoder(c.w).Encode(payload)
+}
+
+func (c *ctx) WriteText(statusCode int, body string) {
+	*c.status = statusCode
+	c.w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	c.w.WriteHeader(statusCode)
+	io.WriteString(c.w, body)
+}
+
+func (c *ctx) Redirect(statusCode int, url string) {
+	*c.status = statusCode
+	http.Redirect(c.w, c.r, url, statusCode)
+}
+
+func (c *ctx) StatusCode() int { return *c.status }
@@ -0,0 +1,49 @@
+// Package router abstracts the HTTP request/response surface that handlers
+// need, so the same handlers can run on more than one server backend
+// (net/http today, fasthttp for high-throughput deployments).
+package router
+
+import "context"
+
+// Ctx is the per-request handle passed to a Handler. It deliberately exposes
+// only what chirpy's handlers use, not the full request/response API of any
+// particular backend.
+type Ctx interface {
+	// Context returns the request-scoped context.Context, carrying
+	// values such as the authenticated user ID.
+	Context() context.Context
+	// WithContext returns a copy of the Ctx carrying ctx.
+	WithContext(ctx context.Context) Ctx
+
+	Method() string
+	Path() string
+	PathValue(name string) string
+	Query(name string) string
+	Header(name string) string
+
+	DecodeJSON(dst interface{}) error
+	JSON(statusCode int, payload interface{})
+	WriteText(statusCode int, body string)
+	Redirect(statusCode int, url string)
+	// StatusCode returns the status code of the last response written to
+	// this Ctx, or 0 if nothing has been written yet.
+	StatusCode() int
+}
+
+// Handler processes a single request via its Ctx.
+type Handler func(Ctx)
+
+// Router registers handlers by method and path pattern and serves them.
+// Path patterns use the same "{name}" wildcard syntax as net/http's
+// ServeMux (Go 1.22+).
+type Router interface {
+	// Handle registers h for method+pattern, e.g. Handle("GET", "/api/chirps/{chirpID}", h).
+	Handle(method, pattern string, h Handler)
+	// HandleAny registers h for pattern regardless of method.
+	HandleAny(pattern string, h Handler)
+	// HandleFiles serves the contents of dir under pathPrefix, calling onHit
+	// (if non-nil) once per served request.
+	HandleFiles(pathPrefix, dir string, onHit func())
+	// ListenAndServe starts the server on addr, blocking until it exits.
+	ListenAndServe(addr string) error
+}
@@ -0,0 +1,81 @@
+package router_test
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/winkles99/chirpy/internal/httperr"
+	"github.com/winkles99/chirpy/internal/router"
+	"github.com/winkles99/chirpy/internal/router/fasthttprouter"
+	"github.com/winkles99/chirpy/internal/router/nethttprouter"
+)
+
+// TestHttperrStatusCodeAcrossBackends runs the same httperr.Middleware- and
+// WrapHandler-wrapped handlers against every router.Router implementation
+// and asserts they produce identical status codes. This guards against a
+// backend's Ctx.StatusCode() disagreeing with the "0 if nothing has been
+// written yet" contract documented on router.Ctx: fasthttprouter once read
+// fasthttp's Response.StatusCode() directly, which defaults to 200, so an
+// httperr.Middleware handler that returned an error without writing its own
+// body silently turned into a 200 instead of the error status.
+func TestHttperrStatusCodeAcrossBackends(t *testing.T) {
+	backends := map[string]router.Router{
+		"nethttprouter":  nethttprouter.New(),
+		"fasthttprouter": fasthttprouter.New(),
+	}
+
+	for name, rt := range backends {
+		rt.Handle("GET", "/middleware-error", httperr.Middleware(func(c router.Ctx) error {
+			return httperr.Wrap(nil, 403, "Forbidden")
+		}))
+		rt.Handle("GET", "/wrap-handler-error", httperr.WrapHandler(func(c router.Ctx) {
+			c.JSON(429, map[string]string{"error": "rate limited"})
+		}))
+
+		addr := startRouter(t, rt)
+
+		for path, want := range map[string]int{
+			"/middleware-error":   403,
+			"/wrap-handler-error": 429,
+		} {
+			resp, err := http.Get(fmt.Sprintf("http://%s%s", addr, path))
+			if err != nil {
+				t.Fatalf("%s: GET %s: %v", name, path, err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != want {
+				t.Errorf("%s: GET %s: status = %d, want %d", name, path, resp.StatusCode, want)
+			}
+		}
+	}
+}
+
+// startRouter starts rt listening on an ephemeral loopback port and returns
+// its address once it's accepting connections.
+func startRouter(t *testing.T, rt router.Router) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("reserve port: %v", err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	go rt.ListenAndServe(addr)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		conn, err := net.Dial("tcp4", addr)
+		if err == nil {
+			conn.Close()
+			return addr
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("router on %s never started accepting connections", addr)
+	return ""
+}
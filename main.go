@@ -1,33 +1,52 @@
 package main
 
 import (
-	"context"
+	"crypto/subtle"
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
-	"sync/atomic"
 	"time"
 	"unicode"
 
+	"github.com/google/uuid"
 	_ "github.com/lib/pq"
 	"github.com/joho/godotenv"
+	"github.com/winkles99/chirpy/internal/auth"
 	"github.com/winkles99/chirpy/internal/database"
+	"github.com/winkles99/chirpy/internal/httperr"
+	"github.com/winkles99/chirpy/internal/metrics"
+	"github.com/winkles99/chirpy/internal/pow"
+	"github.com/winkles99/chirpy/internal/router"
+	"github.com/winkles99/chirpy/internal/router/fasthttprouter"
+	"github.com/winkles99/chirpy/internal/router/nethttprouter"
+)
+
+// Default and maximum page size for chirp listing
+const (
+	defaultChirpsLimit = 20
+	maxChirpsLimit     = 100
+)
+
+// Access and refresh token lifetimes
+const (
+	accessTokenExpiry  = time.Hour
+	refreshTokenExpiry = 60 * 24 * time.Hour
+)
+
+// Default proof-of-work difficulty and the size of the consumed-seed LRU,
+// used when the corresponding env vars are unset.
+const (
+	defaultPoWDifficulty  = 20
+	powConsumedSeedsCache = 10000
 )
 
 // Profanity list
 var profanity = []string{"kerfuffle", "sharbert", "fornax"}
 
-// Helper to respond with JSON
-func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	json.NewEncoder(w).Encode(payload)
-}
-
 // Strip punctuation for word matching
 func stripPunct(word string) string {
 	return strings.Map(func(r rune) rune {
@@ -53,11 +72,16 @@ func cleanChirp(body string) string {
 	return strings.Join(words, " ")
 }
 
-// API config to track hits
+// API config
 type apiConfig struct {
-	fileserverHits atomic.Int32
-	db             *database.Queries
-	Platform       string
+	db        *database.Queries
+	Platform  string
+	JWTSecret string
+	PolkaKey  string
+
+	powVerifier   *pow.Verifier
+	powSecret     string
+	powDifficulty map[string]int
 }
 
 // Request struct
@@ -72,105 +96,655 @@ type errorResponse struct {
 
 // User struct for API response
 type User struct {
+	ID          string `json:"id"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+	Email       string `json:"email"`
+	IsChirpyRed bool   `json:"is_chirpy_red"`
+}
+
+// Chirp struct for API response
+type Chirp struct {
 	ID        string `json:"id"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
-	Email     string `json:"email"`
+	Body      string `json:"body"`
+	AuthorID  string `json:"author_id"`
 }
 
-// Middleware to increment hit counter
-func (cfg *apiConfig) middlewareMetricsInc(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		cfg.fileserverHits.Add(1)
-		next.ServeHTTP(w, r)
-	})
+func userToResponse(user database.User) User {
+	return User{
+		ID:          user.ID.String(),
+		CreatedAt:   user.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   user.UpdatedAt.Format(time.RFC3339),
+		Email:       user.Email,
+		IsChirpyRed: user.IsChirpyRed,
+	}
+}
+
+func chirpToResponse(chirp database.Chirp) Chirp {
+	return Chirp{
+		ID:        chirp.ID.String(),
+		CreatedAt: chirp.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: chirp.UpdatedAt.Format(time.RFC3339),
+		Body:      chirp.Body,
+		AuthorID:  chirp.AuthorID.String(),
+	}
+}
+
+// encodeChirpCursor builds an opaque ?cursor= value for chirp list
+// pagination. It carries both createdAt and id, matching the
+// (created_at, id) tuple comparison ListChirpsAsc/Desc use to break ties
+// between chirps created in the same instant.
+func encodeChirpCursor(createdAt time.Time, id uuid.UUID) string {
+	return createdAt.Format(time.RFC3339Nano) + "," + id.String()
+}
+
+// decodeChirpCursor parses a cursor built by encodeChirpCursor.
+func decodeChirpCursor(raw string) (time.Time, uuid.UUID, error) {
+	createdAtRaw, idRaw, ok := strings.Cut(raw, ",")
+	if !ok {
+		return time.Time{}, uuid.Nil, fmt.Errorf("malformed cursor %q", raw)
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtRaw)
+	if err != nil {
+		return time.Time{}, uuid.Nil, err
+	}
+
+	id, err := uuid.Parse(idRaw)
+	if err != nil {
+		return time.Time{}, uuid.Nil, err
+	}
+
+	return createdAt, id, nil
+}
+
+// parseChirpsLimit parses the ?limit= query param, defaulting to
+// defaultChirpsLimit and clamping to maxChirpsLimit.
+func parseChirpsLimit(raw string) (int, error) {
+	if raw == "" {
+		return defaultChirpsLimit, nil
+	}
+	parsed, err := strconv.Atoi(raw)
+	if err != nil || parsed <= 0 {
+		return 0, fmt.Errorf("invalid limit %q", raw)
+	}
+	if parsed > maxChirpsLimit {
+		parsed = maxChirpsLimit
+	}
+	return parsed, nil
+}
+
+// parseChirpAuthorID parses the ?author_id= query param into a NullUUID,
+// leaving it invalid when raw is empty.
+func parseChirpAuthorID(raw string) (uuid.NullUUID, error) {
+	if raw == "" {
+		return uuid.NullUUID{}, nil
+	}
+	parsed, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.NullUUID{}, err
+	}
+	return uuid.NullUUID{UUID: parsed, Valid: true}, nil
+}
+
+// Middleware requiring a valid access JWT, injecting the user ID into the request context
+func (cfg *apiConfig) requireBearerJWT(next router.Handler) router.Handler {
+	return func(c router.Ctx) {
+		tokenString, err := auth.GetBearerToken(c.Header("Authorization"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, errorResponse{Error: "Unauthorized"})
+			return
+		}
+
+		userID, err := auth.ValidateJWT(tokenString, cfg.JWTSecret)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, errorResponse{Error: "Unauthorized"})
+			return
+		}
+
+		next(c.WithContext(httperr.WithUserID(c.Context(), userID)))
+	}
+}
+
+// Middleware requiring a solved proof-of-work challenge for endpoint, read
+// from the X-PoW header. Disabled when Platform is "dev".
+func (cfg *apiConfig) requirePoW(endpoint string, next router.Handler) router.Handler {
+	return func(c router.Ctx) {
+		if cfg.Platform == "dev" {
+			next(c)
+			return
+		}
+
+		if err := cfg.powVerifier.Verify(c.Header("X-PoW"), cfg.powDifficulty[endpoint]); err != nil {
+			c.JSON(http.StatusTooManyRequests, errorResponse{Error: err.Error()})
+			return
+		}
+
+		next(c)
+	}
+}
+
+// requirePoWHandler is requirePoW for handlers that report failure via their
+// return value (httperr.Handler) instead of writing their own response.
+func (cfg *apiConfig) requirePoWHandler(endpoint string, next httperr.Handler) httperr.Handler {
+	return func(c router.Ctx) error {
+		if cfg.Platform == "dev" {
+			return next(c)
+		}
+
+		if err := cfg.powVerifier.Verify(c.Header("X-PoW"), cfg.powDifficulty[endpoint]); err != nil {
+			return httperr.Wrap(err, http.StatusTooManyRequests, err.Error())
+		}
+
+		return next(c)
+	}
+}
+
+// Proof-of-work challenge handler. ?for=users|login|chirps selects which
+// endpoint's configured difficulty to issue; defaults to "users". See
+// pow.Challenge for the X-PoW header format clients must solve and submit.
+func (cfg *apiConfig) handlerPoWChallenge(c router.Ctx) {
+	endpoint := c.Query("for")
+	difficulty, ok := cfg.powDifficulty[endpoint]
+	if !ok {
+		difficulty = cfg.powDifficulty["users"]
+	}
+
+	challenge, err := pow.NewChallenge(cfg.powSecret, difficulty)
+	if err != nil {
+		log.Printf("Failed to issue PoW challenge: %v", err)
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: "Failed to issue challenge"})
+		return
+	}
+
+	c.JSON(http.StatusOK, challenge)
 }
 
 // Metrics handler
-func (cfg *apiConfig) handlerMetrics(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-	hits := cfg.fileserverHits.Load()
+func (cfg *apiConfig) handlerMetrics(c router.Ctx) {
+	hits := metrics.FileServerHits()
+
+	var rows strings.Builder
+	for _, r := range metrics.TopRoutesByP95(5) {
+		fmt.Fprintf(&rows, "      <tr><td>%s</td><td>%d</td><td>%.2f</td></tr>\n", r.Route, r.Count, r.P95Ms)
+	}
+
 	html := fmt.Sprintf(`
 <html>
   <body>
     <h1>Welcome, Chirpy Admin</h1>
     <p>Chirpy has been visited %d times!</p>
+    <h2>Top routes by p95 latency</h2>
+    <table>
+      <tr><th>Route</th><th>Samples</th><th>p95 (ms)</th></tr>
+%s    </table>
   </body>
 </html>
-`, hits)
-	fmt.Fprint(w, html)
+`, hits, rows.String())
+	c.WriteText(http.StatusOK, html)
 }
 
-// Reset metrics and delete users
-func (cfg *apiConfig) handlerReset(w http.ResponseWriter, r *http.Request) {
-	cfg.fileserverHits.Store(0)
+// Prometheus text-format metrics, scraped at /metrics.
+func (cfg *apiConfig) handlerPrometheusMetrics(c router.Ctx) {
+	body, err := metrics.Gather()
+	if err != nil {
+		log.Printf("Failed to gather metrics: %v", err)
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: "Failed to gather metrics"})
+		return
+	}
 
+	c.WriteText(http.StatusOK, body)
+}
+
+// Delete all users (dev only). Metrics, including the file server hit
+// count, are not reset.
+func (cfg *apiConfig) handlerReset(c router.Ctx) error {
 	if cfg.Platform != "dev" {
-		w.WriteHeader(http.StatusForbidden)
-		respondWithJSON(w, http.StatusForbidden, errorResponse{Error: "Forbidden"})
+		return httperr.Wrap(nil, http.StatusForbidden, "Forbidden")
+	}
+
+	defer metrics.ObserveDBQuery("DeleteAllUsers")()
+	if err := cfg.db.DeleteAllUsers(c.Context()); err != nil {
+		return httperr.Wrap(err, http.StatusInternalServerError, "Failed to delete users")
+	}
+
+	c.JSON(http.StatusOK, map[string]string{"status": "Users reset"})
+	return nil
+}
+
+// Validate chirp handler
+func (cfg *apiConfig) handlerValidateChirp(c router.Ctx) error {
+	var req chirpRequest
+	if err := c.DecodeJSON(&req); err != nil {
+		return httperr.Wrap(err, http.StatusBadRequest, "Something went wrong")
+	}
+
+	if len(req.Body) > 140 {
+		return httperr.Wrap(nil, http.StatusBadRequest, "Chirp is too long")
+	}
+
+	cleaned := cleanChirp(req.Body)
+	c.JSON(http.StatusOK, map[string]string{"cleaned_body": cleaned})
+	return nil
+}
+
+// Create user handler
+func (cfg *apiConfig) handlerCreateUser(c router.Ctx) error {
+	var params struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+	if err := c.DecodeJSON(&params); err != nil {
+		return httperr.Wrap(err, http.StatusBadRequest, "Invalid request")
+	}
+
+	hashedPassword, err := auth.HashPassword(params.Password)
+	if err != nil {
+		return httperr.Wrap(err, http.StatusInternalServerError, "Failed to create user")
+	}
+
+	defer metrics.ObserveDBQuery("CreateUser")()
+	user, err := cfg.db.CreateUser(c.Context(), database.CreateUserParams{
+		Email:          params.Email,
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		return httperr.Wrap(err, http.StatusInternalServerError, "Failed to create user")
+	}
+
+	c.JSON(http.StatusCreated, userToResponse(user))
+	return nil
+}
+
+// dummyPasswordHash is a valid (but otherwise meaningless) bcrypt hash,
+// compared against on an unknown email in handlerLogin so that branch pays
+// the same bcrypt cost as a known email with a wrong password.
+const dummyPasswordHash = "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy"
+
+// Login handler, returns a short-lived access token and a long-lived refresh token
+func (cfg *apiConfig) handlerLogin(c router.Ctx) {
+	var params struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+	if err := c.DecodeJSON(&params); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "Invalid request"})
+		return
+	}
+
+	observeUserLookup := metrics.ObserveDBQuery("GetUserByEmail")
+	user, err := cfg.db.GetUserByEmail(c.Context(), params.Email)
+	observeUserLookup()
+	if err != nil {
+		// Compare against a fixed hash anyway so an unknown email costs the
+		// same bcrypt work as a known one, instead of letting a caller
+		// enumerate registered emails by timing /api/login.
+		auth.CheckPasswordHash(params.Password, dummyPasswordHash)
+		c.JSON(http.StatusUnauthorized, errorResponse{Error: "Incorrect email or password"})
 		return
 	}
 
-	if err := cfg.db.DeleteAllUsers(context.Background()); err != nil {
-		respondWithJSON(w, http.StatusInternalServerError, errorResponse{Error: "Failed to delete users"})
+	if err := auth.CheckPasswordHash(params.Password, user.HashedPassword); err != nil {
+		c.JSON(http.StatusUnauthorized, errorResponse{Error: "Incorrect email or password"})
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, map[string]string{"status": "Metrics and users reset"})
+	accessToken, err := auth.MakeJWT(user.ID, cfg.JWTSecret, accessTokenExpiry)
+	if err != nil {
+		log.Printf("Failed to create access token: %v", err)
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: "Failed to log in"})
+		return
+	}
+
+	refreshToken, err := auth.MakeRefreshToken()
+	if err != nil {
+		log.Printf("Failed to create refresh token: %v", err)
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: "Failed to log in"})
+		return
+	}
+
+	observeRefreshTokenCreate := metrics.ObserveDBQuery("CreateRefreshToken")
+	_, err = cfg.db.CreateRefreshToken(c.Context(), database.CreateRefreshTokenParams{
+		Token:     refreshToken,
+		UserID:    user.ID,
+		ExpiresAt: time.Now().UTC().Add(refreshTokenExpiry),
+	})
+	observeRefreshTokenCreate()
+	if err != nil {
+		log.Printf("Failed to store refresh token: %v", err)
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: "Failed to log in"})
+		return
+	}
+
+	c.JSON(http.StatusOK, struct {
+		User
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+	}{
+		User:         userToResponse(user),
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+	})
 }
 
-// Validate chirp handler
-func (cfg *apiConfig) handlerValidateChirp(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		w.WriteHeader(http.StatusMethodNotAllowed)
+// Refresh handler, exchanges a valid refresh token for a new access token
+func (cfg *apiConfig) handlerRefresh(c router.Ctx) {
+	refreshToken, err := auth.GetBearerToken(c.Header("Authorization"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, errorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	defer metrics.ObserveDBQuery("GetUserFromRefreshToken")()
+	user, err := cfg.db.GetUserFromRefreshToken(c.Context(), refreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, errorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	accessToken, err := auth.MakeJWT(user.ID, cfg.JWTSecret, accessTokenExpiry)
+	if err != nil {
+		log.Printf("Failed to create access token: %v", err)
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: "Failed to refresh"})
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]string{"token": accessToken})
+}
+
+// Revoke handler, invalidates a refresh token
+func (cfg *apiConfig) handlerRevoke(c router.Ctx) {
+	refreshToken, err := auth.GetBearerToken(c.Header("Authorization"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, errorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	defer metrics.ObserveDBQuery("RevokeRefreshToken")()
+	if err := cfg.db.RevokeRefreshToken(c.Context(), refreshToken); err != nil {
+		log.Printf("Failed to revoke refresh token: %v", err)
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: "Failed to revoke token"})
+		return
+	}
+
+	c.WriteText(http.StatusNoContent, "")
+}
+
+// Update the authenticated user's email and password
+func (cfg *apiConfig) handlerUpdateUser(c router.Ctx) {
+	userID, ok := httperr.UserIDFromContext(c.Context())
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	var params struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+	if err := c.DecodeJSON(&params); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "Invalid request"})
+		return
+	}
+
+	hashedPassword, err := auth.HashPassword(params.Password)
+	if err != nil {
+		log.Printf("Failed to hash password: %v", err)
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: "Failed to update user"})
+		return
+	}
+
+	defer metrics.ObserveDBQuery("UpdateUserEmailPassword")()
+	user, err := cfg.db.UpdateUserEmailPassword(c.Context(), database.UpdateUserEmailPasswordParams{
+		ID:             userID,
+		Email:          params.Email,
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		log.Printf("Failed to update user: %v", err)
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: "Failed to update user"})
+		return
+	}
+
+	c.JSON(http.StatusOK, userToResponse(user))
+}
+
+// Polka webhook handler, upgrades a user to Chirpy Red on a "user.upgraded" event
+func (cfg *apiConfig) handlerPolkaWebhook(c router.Ctx) {
+	authHeader := c.Header("Authorization")
+	apiKey := strings.TrimPrefix(authHeader, "ApiKey ")
+	if apiKey == authHeader || subtle.ConstantTimeCompare([]byte(apiKey), []byte(cfg.PolkaKey)) != 1 {
+		c.JSON(http.StatusUnauthorized, errorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	var params struct {
+		Event string `json:"event"`
+		Data  struct {
+			UserID string `json:"user_id"`
+		} `json:"data"`
+	}
+	if err := c.DecodeJSON(&params); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "Invalid request"})
+		return
+	}
+
+	if params.Event != "user.upgraded" {
+		c.WriteText(http.StatusNoContent, "")
+		return
+	}
+
+	userID, err := uuid.Parse(params.Data.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "Invalid user_id"})
+		return
+	}
+
+	defer metrics.ObserveDBQuery("UpgradeUserToChirpyRed")()
+	if _, err := cfg.db.UpgradeUserToChirpyRed(c.Context(), userID); err != nil {
+		c.JSON(http.StatusNotFound, errorResponse{Error: "User not found"})
+		return
+	}
+
+	c.WriteText(http.StatusNoContent, "")
+}
+
+// Create chirp handler
+func (cfg *apiConfig) handlerCreateChirp(c router.Ctx) {
+	authorID, ok := httperr.UserIDFromContext(c.Context())
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse{Error: "Unauthorized"})
 		return
 	}
 
 	var req chirpRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithJSON(w, http.StatusBadRequest, errorResponse{Error: "Something went wrong"})
+	if err := c.DecodeJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "Something went wrong"})
 		return
 	}
 
 	if len(req.Body) > 140 {
-		respondWithJSON(w, http.StatusBadRequest, errorResponse{Error: "Chirp is too long"})
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "Chirp is too long"})
 		return
 	}
 
 	cleaned := cleanChirp(req.Body)
-	respondWithJSON(w, http.StatusOK, map[string]string{"cleaned_body": cleaned})
+	defer metrics.ObserveDBQuery("CreateChirp")()
+	chirp, err := cfg.db.CreateChirp(c.Context(), database.CreateChirpParams{
+		Body:     cleaned,
+		AuthorID: authorID,
+	})
+	if err != nil {
+		log.Printf("Failed to create chirp: %v", err)
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: "Failed to create chirp"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, chirpToResponse(chirp))
 }
 
-// Create user handler
-func (cfg *apiConfig) handlerCreateUser(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		w.WriteHeader(http.StatusMethodNotAllowed)
+// List chirps handler, supports ?author_id=, ?sort=asc|desc, ?limit=, ?cursor=
+func (cfg *apiConfig) handlerListChirps(c router.Ctx) {
+	limit, err := parseChirpsLimit(c.Query("limit"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "Invalid limit"})
 		return
 	}
 
-	var params struct {
-		Email string `json:"email"`
+	authorID, err := parseChirpAuthorID(c.Query("author_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "Invalid author_id"})
+		return
+	}
+
+	var (
+		cursorCreatedAt sql.NullTime
+		cursorID        uuid.NullUUID
+	)
+	if raw := c.Query("cursor"); raw != "" {
+		createdAt, id, err := decodeChirpCursor(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse{Error: "Invalid cursor"})
+			return
+		}
+		cursorCreatedAt = sql.NullTime{Time: createdAt, Valid: true}
+		cursorID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	sort := c.Query("sort")
+	var chirps []database.Chirp
+	if sort == "desc" {
+		observeListChirps := metrics.ObserveDBQuery("ListChirpsDesc")
+		chirps, err = cfg.db.ListChirpsDesc(c.Context(), database.ListChirpsDescParams{
+			AuthorID:        authorID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			LimitCount:      int32(limit),
+		})
+		observeListChirps()
+	} else {
+		observeListChirps := metrics.ObserveDBQuery("ListChirpsAsc")
+		chirps, err = cfg.db.ListChirpsAsc(c.Context(), database.ListChirpsAscParams{
+			AuthorID:        authorID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			LimitCount:      int32(limit),
+		})
+		observeListChirps()
 	}
-	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
-		respondWithJSON(w, http.StatusBadRequest, errorResponse{Error: "Invalid request"})
+	if err != nil {
+		log.Printf("Failed to list chirps: %v", err)
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: "Failed to list chirps"})
+		return
+	}
+
+	resp := make([]Chirp, len(chirps))
+	for i, chirp := range chirps {
+		resp[i] = chirpToResponse(chirp)
+	}
+
+	nextCursor := ""
+	if len(chirps) == limit {
+		last := chirps[len(chirps)-1]
+		nextCursor = encodeChirpCursor(last.CreatedAt, last.ID)
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"chirps":      resp,
+		"next_cursor": nextCursor,
+	})
+}
+
+// Get a single chirp handler
+func (cfg *apiConfig) handlerGetChirp(c router.Ctx) {
+	chirpID, err := uuid.Parse(c.PathValue("chirpID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "Invalid chirp ID"})
+		return
+	}
+
+	defer metrics.ObserveDBQuery("GetChirp")()
+	chirp, err := cfg.db.GetChirp(c.Context(), chirpID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse{Error: "Chirp not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, chirpToResponse(chirp))
+}
+
+// Delete a chirp handler, only the author may delete their own chirp
+func (cfg *apiConfig) handlerDeleteChirp(c router.Ctx) {
+	requesterID, ok := httperr.UserIDFromContext(c.Context())
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	chirpID, err := uuid.Parse(c.PathValue("chirpID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "Invalid chirp ID"})
 		return
 	}
 
-	user, err := cfg.db.CreateUser(r.Context(), params.Email)
+	observeGetChirp := metrics.ObserveDBQuery("GetChirp")
+	chirp, err := cfg.db.GetChirp(c.Context(), chirpID)
+	observeGetChirp()
 	if err != nil {
-		log.Printf("Failed to create user: %v", err)
-		respondWithJSON(w, http.StatusInternalServerError, errorResponse{Error: "Failed to create user"})
+		c.JSON(http.StatusNotFound, errorResponse{Error: "Chirp not found"})
 		return
 	}
 
-	resp := User{
-		ID:        user.ID.String(),
-		CreatedAt: user.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
-		Email:     user.Email,
+	if requesterID != chirp.AuthorID {
+		c.JSON(http.StatusForbidden, errorResponse{Error: "You cannot delete this chirp"})
+		return
+	}
+
+	defer metrics.ObserveDBQuery("DeleteChirp")()
+	if err := cfg.db.DeleteChirp(c.Context(), chirpID); err != nil {
+		log.Printf("Failed to delete chirp: %v", err)
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: "Failed to delete chirp"})
+		return
 	}
 
-	respondWithJSON(w, http.StatusCreated, resp)
+	c.WriteText(http.StatusNoContent, "")
+}
+
+// envIntOrDefault parses the env var name as an int, falling back to def if
+// it is unset or invalid.
+func envIntOrDefault(name string, def int) int {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+	parsed, err := strconv.Atoi(raw)
+	if err != nil {
+		return def
+	}
+	return parsed
+}
+
+// middlewareObserve wraps h with Prometheus instrumentation labeled route,
+// used at route registration time so each handler it wraps contributes
+// counters, latency histograms, and an in-flight gauge.
+func middlewareObserve(route string, h router.Handler) router.Handler {
+	return metrics.Observe(route, h)
+}
+
+// newRouter builds the server engine selected by SERVER_ENGINE (default "nethttp").
+func newRouter(engine string) router.Router {
+	switch engine {
+	case "fasthttp":
+		log.Println("Using fasthttp server engine")
+		return fasthttprouter.New()
+	case "", "nethttp":
+		return nethttprouter.New()
+	default:
+		log.Fatalf("Unknown SERVER_ENGINE %q (want nethttp or fasthttp)", engine)
+		return nil
+	}
 }
 
 func main() {
@@ -200,63 +774,77 @@ func main() {
 	// Create SQLC queries instance
 	dbQueries := database.New(db)
 
+	powSecret := os.Getenv("POW_SECRET")
+	powDifficulty := map[string]int{
+		"users":  envIntOrDefault("POW_DIFFICULTY_USERS", defaultPoWDifficulty),
+		"login":  envIntOrDefault("POW_DIFFICULTY_LOGIN", defaultPoWDifficulty),
+		"chirps": envIntOrDefault("POW_DIFFICULTY_CHIRPS", defaultPoWDifficulty),
+	}
+
 	// Initialize API config
 	apiCfg := &apiConfig{
-		db:       dbQueries,
-		Platform: os.Getenv("PLATFORM"),
+		db:        dbQueries,
+		Platform:  os.Getenv("PLATFORM"),
+		JWTSecret: os.Getenv("JWT_SECRET"),
+		PolkaKey:  os.Getenv("POLKA_KEY"),
+
+		powVerifier:   pow.NewVerifier(powSecret, powConsumedSeedsCache),
+		powSecret:     powSecret,
+		powDifficulty: powDifficulty,
 	}
 
-	// Setup HTTP mux
-	mux := http.NewServeMux()
+	// Report 5xx errors and panics to Sentry, if configured
+	if err := httperr.InitSentry(os.Getenv("SENTRY_DSN")); err != nil {
+		log.Printf("Failed to initialize Sentry: %v", err)
+	}
+
+	// Setup the router; defaults to net/http, or fasthttp under sustained write load
+	rt := newRouter(os.Getenv("SERVER_ENGINE"))
 
 	// File server
-	fsHandler := http.StripPrefix("/app/", http.FileServer(http.Dir(".")))
-	mux.Handle("/app/", apiCfg.middlewareMetricsInc(fsHandler))
+	rt.HandleFiles("/app/", ".", metrics.RecordFileServerHit)
 
 	// Health check
-	mux.HandleFunc("/api/healthz", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			return
-		}
-		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
+	rt.Handle("GET", "/api/healthz", middlewareObserve("GET /api/healthz", httperr.WrapHandler(func(c router.Ctx) {
+		c.WriteText(http.StatusOK, "OK")
+	})))
 
 	// Admin endpoints
-	mux.HandleFunc("/admin/metrics", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			return
-		}
-		apiCfg.handlerMetrics(w, r)
-	})
+	rt.Handle("GET", "/admin/metrics", middlewareObserve("GET /admin/metrics", httperr.WrapHandler(apiCfg.handlerMetrics)))
+	rt.Handle("POST", "/admin/reset", middlewareObserve("POST /admin/reset", httperr.Middleware(apiCfg.handlerReset)))
 
-	mux.HandleFunc("/admin/reset", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			return
-		}
-		apiCfg.handlerReset(w, r)
-	})
+	// Prometheus scrape endpoint
+	rt.Handle("GET", "/metrics", httperr.WrapHandler(apiCfg.handlerPrometheusMetrics))
+
+	// Proof-of-work challenge
+	rt.Handle("GET", "/api/pow/challenge", middlewareObserve("GET /api/pow/challenge", httperr.WrapHandler(apiCfg.handlerPoWChallenge)))
 
-	// User creation endpoint
-	mux.HandleFunc("/api/users", apiCfg.handlerCreateUser)
+	// User endpoints
+	rt.Handle("POST", "/api/users", middlewareObserve("POST /api/users", httperr.Middleware(apiCfg.requirePoWHandler("users", apiCfg.handlerCreateUser))))
+	rt.Handle("PUT", "/api/users", middlewareObserve("PUT /api/users", httperr.WrapHandler(apiCfg.requireBearerJWT(apiCfg.handlerUpdateUser))))
+
+	// Auth endpoints
+	rt.Handle("POST", "/api/login", middlewareObserve("POST /api/login", httperr.WrapHandler(apiCfg.requirePoW("login", apiCfg.handlerLogin))))
+	rt.Handle("POST", "/api/refresh", middlewareObserve("POST /api/refresh", httperr.WrapHandler(apiCfg.handlerRefresh)))
+	rt.Handle("POST", "/api/revoke", middlewareObserve("POST /api/revoke", httperr.WrapHandler(apiCfg.handlerRevoke)))
+
+	// Polka webhooks
+	rt.Handle("POST", "/api/polka/webhooks", middlewareObserve("POST /api/polka/webhooks", httperr.WrapHandler(apiCfg.handlerPolkaWebhook)))
 
 	// Root redirect
-	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		http.Redirect(w, r, "/app/", http.StatusFound)
-	})
+	rt.HandleAny("/", httperr.WrapHandler(func(c router.Ctx) {
+		c.Redirect(http.StatusFound, "/app/")
+	}))
 
 	// Chirp validation
-	mux.HandleFunc("/api/validate_chirp", apiCfg.handlerValidateChirp)
+	rt.Handle("POST", "/api/validate_chirp", middlewareObserve("POST /api/validate_chirp", httperr.Middleware(apiCfg.handlerValidateChirp)))
 
-	server := http.Server{
-		Addr:    ":8080",
-		Handler: mux,
-	}
+	// Chirps resource
+	rt.Handle("POST", "/api/chirps", middlewareObserve("POST /api/chirps", httperr.WrapHandler(apiCfg.requirePoW("chirps", apiCfg.requireBearerJWT(apiCfg.handlerCreateChirp)))))
+	rt.Handle("GET", "/api/chirps", middlewareObserve("GET /api/chirps", httperr.WrapHandler(apiCfg.handlerListChirps)))
+	rt.Handle("GET", "/api/chirps/{chirpID}", middlewareObserve("GET /api/chirps/{chirpID}", httperr.WrapHandler(apiCfg.handlerGetChirp)))
+	rt.Handle("DELETE", "/api/chirps/{chirpID}", middlewareObserve("DELETE /api/chirps/{chirpID}", httperr.WrapHandler(apiCfg.requireBearerJWT(apiCfg.handlerDeleteChirp))))
 
 	fmt.Println("Server listening on http://localhost:8080")
-	server.ListenAndServe()
+	log.Fatal(rt.ListenAndServe(":8080"))
 }
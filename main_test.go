@@ -0,0 +1,103 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestChirpCursorRoundTrip(t *testing.T) {
+	wantTime := time.Date(2026, 1, 2, 3, 4, 5, 123456789, time.UTC)
+	wantID := uuid.New()
+
+	cursor := encodeChirpCursor(wantTime, wantID)
+
+	gotTime, gotID, err := decodeChirpCursor(cursor)
+	if err != nil {
+		t.Fatalf("decodeChirpCursor(%q) error = %v", cursor, err)
+	}
+	if !gotTime.Equal(wantTime) {
+		t.Errorf("decodeChirpCursor() time = %v, want %v", gotTime, wantTime)
+	}
+	if gotID != wantID {
+		t.Errorf("decodeChirpCursor() id = %v, want %v", gotID, wantID)
+	}
+}
+
+func TestDecodeChirpCursorRejectsMalformedInput(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{name: "no comma", raw: "not-a-cursor"},
+		{name: "bad timestamp", raw: "not-a-time," + uuid.New().String()},
+		{name: "bad id", raw: time.Now().Format(time.RFC3339Nano) + ",not-a-uuid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := decodeChirpCursor(tt.raw); err == nil {
+				t.Errorf("decodeChirpCursor(%q) error = nil, want error", tt.raw)
+			}
+		})
+	}
+}
+
+func TestParseChirpsLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    int
+		wantErr bool
+	}{
+		{name: "empty uses default", raw: "", want: defaultChirpsLimit},
+		{name: "within range", raw: "5", want: 5},
+		{name: "clamped to max", raw: "1000", want: maxChirpsLimit},
+		{name: "zero is invalid", raw: "0", wantErr: true},
+		{name: "negative is invalid", raw: "-1", wantErr: true},
+		{name: "non-numeric is invalid", raw: "abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseChirpsLimit(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseChirpsLimit(%q) error = nil, want error", tt.raw)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseChirpsLimit(%q) error = %v, want nil", tt.raw, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseChirpsLimit(%q) = %d, want %d", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseChirpAuthorID(t *testing.T) {
+	id := uuid.New()
+
+	got, err := parseChirpAuthorID(id.String())
+	if err != nil {
+		t.Fatalf("parseChirpAuthorID(%q) error = %v", id, err)
+	}
+	if !got.Valid || got.UUID != id {
+		t.Errorf("parseChirpAuthorID(%q) = %+v, want {UUID: %v, Valid: true}", id, got, id)
+	}
+
+	got, err = parseChirpAuthorID("")
+	if err != nil {
+		t.Fatalf("parseChirpAuthorID(\"\") error = %v", err)
+	}
+	if got.Valid {
+		t.Errorf("parseChirpAuthorID(\"\") = %+v, want Valid: false", got)
+	}
+
+	if _, err := parseChirpAuthorID("not-a-uuid"); err == nil {
+		t.Error("parseChirpAuthorID(\"not-a-uuid\") error = nil, want error")
+	}
+}